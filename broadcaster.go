@@ -0,0 +1,61 @@
+package main
+
+import (
+	"sync"
+
+	"github.com/vartanbeno/go-reddit/v2/reddit"
+)
+
+// Event is a single message fanned out to WebSocket subscribers: either a
+// newly observed post or an updated leaderboard for a subreddit.
+type Event struct {
+	Type      string         `json:"type"` // "post" or "leaderboard"
+	Subreddit string         `json:"subreddit"`
+	Post      *reddit.Post   `json:"post,omitempty"`
+	TopPosts  []*reddit.Post `json:"top_posts,omitempty"`
+}
+
+// broadcaster fans out events to any number of subscribers without letting a
+// slow subscriber block the sender (updateStats' main processing loop).
+type broadcaster struct {
+	mu          sync.Mutex
+	subscribers map[chan Event]struct{}
+}
+
+// newBroadcaster creates an empty broadcaster.
+func newBroadcaster() *broadcaster {
+	return &broadcaster{subscribers: make(map[chan Event]struct{})}
+}
+
+// Subscribe registers a new subscriber and returns the channel it should
+// read events from. Callers must Unsubscribe when done to avoid leaking it.
+func (b *broadcaster) Subscribe() chan Event {
+	ch := make(chan Event, 16)
+	b.mu.Lock()
+	b.subscribers[ch] = struct{}{}
+	b.mu.Unlock()
+	return ch
+}
+
+// Unsubscribe removes and closes ch.
+func (b *broadcaster) Unsubscribe(ch chan Event) {
+	b.mu.Lock()
+	if _, ok := b.subscribers[ch]; ok {
+		delete(b.subscribers, ch)
+		close(ch)
+	}
+	b.mu.Unlock()
+}
+
+// Publish delivers event to every current subscriber. A subscriber whose
+// buffer is full is skipped rather than blocking the publisher.
+func (b *broadcaster) Publish(event Event) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for ch := range b.subscribers {
+		select {
+		case ch <- event:
+		default:
+		}
+	}
+}
@@ -0,0 +1,46 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBroadcasterDeliversToSubscribers(t *testing.T) {
+	b := newBroadcaster()
+	ch := b.Subscribe()
+
+	b.Publish(Event{Type: "post", Subreddit: "golang"})
+
+	event := <-ch
+	assert.Equal(t, "post", event.Type)
+	assert.Equal(t, "golang", event.Subreddit)
+}
+
+func TestBroadcasterSkipsSlowSubscribers(t *testing.T) {
+	b := newBroadcaster()
+	ch := b.Subscribe()
+
+	// Publish far more events than the subscriber's buffer can hold without
+	// reading from it; Publish must not block on the full channel.
+	const published = 100
+	for i := 0; i < published; i++ {
+		b.Publish(Event{Type: "post"})
+	}
+
+	delivered := 0
+drain:
+	for {
+		select {
+		case <-ch:
+			delivered++
+		default:
+			break drain
+		}
+	}
+	assert.Less(t, delivered, published, "slow subscriber should have missed some events, not blocked Publish")
+
+	b.Unsubscribe(ch)
+	_, open := <-ch
+	assert.False(t, open)
+}
@@ -2,7 +2,10 @@ package main
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"log"
+	"net/http"
 	"os"
 	"sort"
 	"sync"
@@ -13,14 +16,36 @@ import (
 	"github.com/vartanbeno/go-reddit/v2/reddit"
 )
 
+// errNoStatsStore is returned by Stats' history-query methods when no
+// StatsStore was configured.
+var errNoStatsStore = errors.New("stats: no StatsStore configured")
+
+// errNotStarted is returned by Subscribe when called before Start, since
+// there's no root context yet to derive a per-subreddit context from.
+var errNotStarted = errors.New("stats: Subscribe called before Start")
+
+// RequestCompletionHook is called after every Reddit API request completes,
+// successfully or not, with the raw request/response pair.
+type RequestCompletionHook func(req *http.Request, res *http.Response)
+
 // RedditAPI defines the methods that the Reddit client must implement
 type RedditAPI interface {
-	FetchPosts(subreddit string) ([]*reddit.Post, error)
+	FetchPosts(ctx context.Context, subreddit string) ([]*reddit.Post, error)
+
+	// OnRequestCompleted registers a hook invoked after every request,
+	// mirroring go-reddit's own OnRequestCompleted pattern.
+	OnRequestCompleted(hook RequestCompletionHook)
+
+	// RateLimiter returns the RateLimiter used to pace requests.
+	RateLimiter() RateLimiter
 }
 
 // RedditClient handles communication with Reddit API
 type RedditClient struct {
-	client *reddit.Client
+	client      *reddit.Client
+	rateLimiter RateLimiter
+	hooksMu     sync.Mutex
+	hooks       []RequestCompletionHook
 }
 
 // NewRedditClient creates a new Reddit client
@@ -36,96 +61,371 @@ func NewRedditClient(id, secret, username, password string) (*RedditClient, erro
 	if err != nil {
 		return nil, err
 	}
-	return &RedditClient{client: client}, nil
+	return &RedditClient{
+		client:      client,
+		rateLimiter: NewHeaderRateLimiter(1*time.Second, 10*time.Second),
+	}, nil
+}
+
+// OnRequestCompleted implements RedditAPI.
+func (c *RedditClient) OnRequestCompleted(hook RequestCompletionHook) {
+	c.hooksMu.Lock()
+	defer c.hooksMu.Unlock()
+	c.hooks = append(c.hooks, hook)
+}
+
+// RateLimiter implements RedditAPI.
+func (c *RedditClient) RateLimiter() RateLimiter {
+	return c.rateLimiter
 }
 
 // FetchPosts fetches the latest posts from a subreddit
-func (c *RedditClient) FetchPosts(subreddit string) ([]*reddit.Post, error) {
-	posts, _, err := c.client.Subreddit.NewPosts(context.Background(), subreddit, &reddit.ListOptions{Limit: 100})
+func (c *RedditClient) FetchPosts(ctx context.Context, subreddit string) ([]*reddit.Post, error) {
+	start := time.Now()
+	posts, resp, err := c.client.Subreddit.NewPosts(ctx, subreddit, &reddit.ListOptions{Limit: 100})
+
+	var httpResp *http.Response
+	var httpReq *http.Request
+	statusCode := 0
+	if resp != nil && resp.Response != nil {
+		httpResp = resp.Response
+		httpReq = resp.Response.Request
+		statusCode = resp.Response.StatusCode
+		c.rateLimiter.Update(httpResp)
+	}
+	observeRequest(subreddit, statusCode, time.Since(start))
+
+	c.hooksMu.Lock()
+	hooks := append([]RequestCompletionHook(nil), c.hooks...)
+	c.hooksMu.Unlock()
+	for _, hook := range hooks {
+		hook(httpReq, httpResp)
+	}
+
 	return posts, err
 }
 
-// Stats handles tracking statistics for Reddit posts
-type Stats struct {
-	topPosts  []*reddit.Post
-	userPosts map[string]int
-	postsCh   chan *reddit.Post
-	doneCh    chan bool
-	client    RedditAPI
+// trackedPost tags a fetched post with the subreddit it came from, since
+// postsCh is now shared across every subreddit's fetcher goroutine.
+type trackedPost struct {
+	post      *reddit.Post
 	subreddit string
-	mu        sync.Mutex
 }
 
-// NewStats initializes a new Stats tracker
-func NewStats(client RedditAPI, subreddit string) *Stats {
+// Stats handles tracking statistics for Reddit posts across one or more
+// subreddits.
+type Stats struct {
+	topPosts    map[string]*topHeap // per-subreddit leaderboard, deduplicated by post ID
+	globalTop   []*reddit.Post      // merged leaderboard across all subreddits
+	userPosts   map[string]int
+	postsCh     chan trackedPost
+	client      RedditAPI
+	store       StatsStore
+	initialSubs []string
+	ctx         context.Context
+	cancel      context.CancelFunc
+	subCancels  map[string]context.CancelFunc
+	broadcaster *broadcaster
+	wg          sync.WaitGroup // every fetchPosts goroutine plus updateStats
+	mu          sync.Mutex
+}
+
+// NewStats initializes a new Stats tracker for the given subreddits. store
+// may be nil, in which case only the in-memory leaderboards and per-user
+// counters are kept.
+func NewStats(client RedditAPI, subreddits []string, store StatsStore) *Stats {
 	return &Stats{
-		topPosts:  []*reddit.Post{},
-		userPosts: make(map[string]int),
-		postsCh:   make(chan *reddit.Post),
-		doneCh:    make(chan bool),
-		client:    client,
-		subreddit: subreddit,
+		topPosts:    make(map[string]*topHeap),
+		userPosts:   make(map[string]int),
+		postsCh:     make(chan trackedPost),
+		client:      client,
+		store:       store,
+		initialSubs: subreddits,
+		subCancels:  make(map[string]context.CancelFunc),
+		broadcaster: newBroadcaster(),
+	}
+}
+
+// Subscribe starts tracking sub at runtime, spawning a fetcher goroutine for
+// it without disturbing any subreddit already being tracked.
+func (s *Stats) Subscribe(sub string) error {
+	s.mu.Lock()
+	if s.ctx == nil {
+		s.mu.Unlock()
+		return errNotStarted
+	}
+	if _, exists := s.subCancels[sub]; exists {
+		s.mu.Unlock()
+		return fmt.Errorf("already subscribed to %s", sub)
+	}
+	ctx, cancel := context.WithCancel(s.ctx)
+	s.subCancels[sub] = cancel
+	if _, ok := s.topPosts[sub]; !ok {
+		s.topPosts[sub] = newTopHeap(defaultTopN)
+	}
+	s.mu.Unlock()
+
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		s.fetchPosts(ctx, sub)
+	}()
+	return nil
+}
+
+// Unsubscribe stops tracking sub, cancelling its fetcher goroutine and
+// dropping its leaderboard. Posts for sub still in flight on postsCh are
+// discarded by processPost.
+func (s *Stats) Unsubscribe(sub string) error {
+	s.mu.Lock()
+	cancel, exists := s.subCancels[sub]
+	if !exists {
+		s.mu.Unlock()
+		return fmt.Errorf("not subscribed to %s", sub)
+	}
+	delete(s.subCancels, sub)
+	delete(s.topPosts, sub)
+	s.mergeGlobalTop()
+	s.mu.Unlock()
+
+	cancel()
+	return nil
+}
+
+// TopPostsSince returns the highest scoring posts recorded since t, reading
+// through to the underlying StatsStore.
+func (s *Stats) TopPostsSince(t time.Time, limit int) ([]PostSnapshot, error) {
+	if s.store == nil {
+		return nil, errNoStatsStore
+	}
+	return s.store.TopPostsSince(t, limit)
+}
+
+// UserActivity returns user's snapshots within the trailing window, reading
+// through to the underlying StatsStore.
+func (s *Stats) UserActivity(user string, window time.Duration) ([]PostSnapshot, error) {
+	if s.store == nil {
+		return nil, errNoStatsStore
+	}
+	return s.store.UserActivity(user, window)
+}
+
+// ScoreSeries returns the score-over-time series for a post, reading through
+// to the underlying StatsStore.
+func (s *Stats) ScoreSeries(postID string) ([]PostSnapshot, error) {
+	if s.store == nil {
+		return nil, errNoStatsStore
+	}
+	return s.store.ScoreSeries(postID)
+}
+
+// TopN returns a sorted snapshot of the top k posts tracked for subreddit.
+func (s *Stats) TopN(subreddit string, k int) []*reddit.Post {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	h, ok := s.topPosts[subreddit]
+	if !ok {
+		return nil
+	}
+	return h.TopN(k)
+}
+
+// GlobalTop returns a snapshot of the merged cross-subreddit leaderboard.
+func (s *Stats) GlobalTop() []*reddit.Post {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	out := make([]*reddit.Post, len(s.globalTop))
+	copy(out, s.globalTop)
+	return out
+}
+
+// UserCounts returns a snapshot of each user's post count.
+func (s *Stats) UserCounts() map[string]int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	out := make(map[string]int, len(s.userPosts))
+	for user, count := range s.userPosts {
+		out[user] = count
 	}
+	return out
 }
 
-// Start begins fetching posts and updating stats
-func (s *Stats) Start() {
-	go s.fetchPosts()
-	go s.updateStats()
+// SubscribeEvents registers a new listener for live post and leaderboard
+// events. Callers must pass the returned channel to UnsubscribeEvents when
+// done.
+func (s *Stats) SubscribeEvents() chan Event {
+	return s.broadcaster.Subscribe()
 }
 
-// Stop halts the stats tracking
+// UnsubscribeEvents removes a listener previously returned by SubscribeEvents.
+func (s *Stats) UnsubscribeEvents(ch chan Event) {
+	s.broadcaster.Unsubscribe(ch)
+}
+
+// Start begins fetching posts and updating stats. It acts as a supervisor,
+// spawning one fetcher goroutine per subreddit, all sharing postsCh. ctx
+// governs the lifetime of every goroutine Start spawns; cancelling it (or
+// calling Stop) begins a graceful shutdown.
+func (s *Stats) Start(ctx context.Context) {
+	s.ctx, s.cancel = context.WithCancel(ctx)
+
+	for _, sub := range s.initialSubs {
+		if err := s.Subscribe(sub); err != nil {
+			logrus.Errorf("Error subscribing to %s: %v", sub, err)
+		}
+	}
+
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		s.updateStats(s.ctx)
+	}()
+}
+
+// Stop begins a graceful shutdown: it cancels the context Start derived from
+// its ctx argument, which stops every fetcher goroutine and updateStats. It does not block; call Wait to block
+// until all goroutines have exited.
 func (s *Stats) Stop() {
-	close(s.doneCh)
-	close(s.postsCh)
+	s.cancel()
+}
+
+// Wait blocks until every goroutine spawned by Start has exited.
+func (s *Stats) Wait() {
+	s.wg.Wait()
 }
 
-// fetchPosts continuously fetches posts from Reddit
-func (s *Stats) fetchPosts() {
-	ticker := time.NewTicker(10 * time.Second)
+// fetchPosts continuously fetches posts for a single subreddit from Reddit,
+// adapting its polling interval to the remaining request quota reported by
+// the Reddit API, until ctx is cancelled.
+func (s *Stats) fetchPosts(ctx context.Context, subreddit string) {
+	interval := 10 * time.Second
+	if limiter := s.client.RateLimiter(); limiter != nil {
+		interval = limiter.NextInterval()
+	}
+	ticker := time.NewTicker(interval)
 	defer ticker.Stop()
 
 	for {
 		select {
 		case <-ticker.C:
-			posts, err := s.client.FetchPosts(s.subreddit)
+			posts, err := s.client.FetchPosts(ctx, subreddit)
 			if err != nil {
-				logrus.Errorf("Error fetching posts: %v", err)
+				logrus.Errorf("Error fetching posts for %s: %v", subreddit, err)
 				continue
 			}
 			for _, post := range posts {
-				s.postsCh <- post
+				select {
+				case s.postsCh <- trackedPost{post: post, subreddit: subreddit}:
+				case <-ctx.Done():
+					return
+				}
+			}
+
+			if limiter := s.client.RateLimiter(); limiter != nil {
+				ticker.Reset(limiter.NextInterval())
 			}
-		case <-s.doneCh:
+		case <-ctx.Done():
 			return
 		}
 	}
 }
 
-// updateStats processes incoming posts and updates statistics
-func (s *Stats) updateStats() {
-	for post := range s.postsCh {
-		s.mu.Lock()
-		s.userPosts[post.Author]++
-		s.topPosts = append(s.topPosts, post)
-
-		sort.Slice(s.topPosts, func(i, j int) bool {
-			return s.topPosts[i].Score > s.topPosts[j].Score
-		})
+// updateStats processes incoming posts and updates statistics until ctx is
+// cancelled, at which point it drains any posts a fetcher is still handing
+// over on postsCh before returning.
+func (s *Stats) updateStats(ctx context.Context) {
+	for {
+		select {
+		case tracked := <-s.postsCh:
+			s.processPost(tracked)
+		case <-ctx.Done():
+			s.drainPosts()
+			return
+		}
+	}
+}
 
-		if len(s.topPosts) > 10 {
-			s.topPosts = s.topPosts[:10]
+// drainPosts processes whatever posts can be received from postsCh without
+// blocking. postsCh is unbuffered, so posts that a fetcher has not yet sent
+// when it sees ctx cancelled are not processed.
+func (s *Stats) drainPosts() {
+	for {
+		select {
+		case tracked := <-s.postsCh:
+			s.processPost(tracked)
+		default:
+			return
 		}
+	}
+}
 
-		s.logStats()
+// processPost updates the leaderboards and counters for a single post and
+// fans it out to subscribers and the StatsStore.
+func (s *Stats) processPost(tracked trackedPost) {
+	post, subreddit := tracked.post, tracked.subreddit
+
+	s.mu.Lock()
+	if _, subscribed := s.subCancels[subreddit]; !subscribed {
+		// Unsubscribe raced fetchPosts's select and dropped this subreddit's
+		// leaderboard already; don't resurrect it for a post that was
+		// already in flight.
 		s.mu.Unlock()
+		return
+	}
+	s.userPosts[post.Author]++
+	if _, ok := s.topPosts[subreddit]; !ok {
+		s.topPosts[subreddit] = newTopHeap(defaultTopN)
+	}
+	s.topPosts[subreddit].Add(post)
+
+	s.mergeGlobalTop()
+	s.logStats()
+	subredditTop := s.topPosts[subreddit].TopN(defaultTopN)
+	s.mu.Unlock()
+
+	s.broadcaster.Publish(Event{Type: "post", Subreddit: subreddit, Post: post})
+	s.broadcaster.Publish(Event{Type: "leaderboard", Subreddit: subreddit, TopPosts: subredditTop})
+
+	if s.store != nil {
+		snapshot := PostSnapshot{
+			ID:        post.FullID,
+			Subreddit: subreddit,
+			Title:     post.Title,
+			Author:    post.Author,
+			Score:     post.Score,
+			Comments:  post.NumberOfComments,
+			Timestamp: time.Now(),
+		}
+		if err := s.store.SavePost(snapshot); err != nil {
+			logrus.Errorf("Error saving post snapshot: %v", err)
+		}
+	}
+}
+
+// mergeGlobalTop rebuilds the cross-subreddit leaderboard from each
+// subreddit's own top posts. Callers must hold s.mu.
+func (s *Stats) mergeGlobalTop() {
+	merged := make([]*reddit.Post, 0, len(s.topPosts)*defaultTopN)
+	for _, h := range s.topPosts {
+		merged = append(merged, h.TopN(defaultTopN)...)
+	}
+
+	sort.Slice(merged, func(i, j int) bool {
+		return merged[i].Score > merged[j].Score
+	})
+	if len(merged) > defaultTopN {
+		merged = merged[:defaultTopN]
 	}
+	s.globalTop = merged
 }
 
 // logStats outputs the current statistics
 func (s *Stats) logStats() {
-	logrus.Infof("Top 10 Posts by Upvotes:")
-	for _, post := range s.topPosts {
+	logrus.Infof("Top 10 Posts (global):")
+	for _, post := range s.globalTop {
 		logrus.Infof("%s - Upvotes: %d", post.Title, post.Score)
 	}
 
@@ -152,11 +452,35 @@ func main() {
 		log.Fatalf("Failed to create Reddit client: %v", err)
 	}
 
-	stats := NewStats(client, "golang")
-	stats.Start()
+	client.OnRequestCompleted(func(req *http.Request, res *http.Response) {
+		if res != nil && res.StatusCode == http.StatusTooManyRequests {
+			logrus.Warnf("Reddit API rate limited request to %s", req.URL)
+		}
+	})
+
+	store, err := NewSQLiteStore(os.Getenv("STATS_DB_PATH"))
+	if err != nil {
+		log.Fatalf("Failed to open stats store: %v", err)
+	}
+	defer store.Close()
+
+	stats := NewStats(client, []string{"golang"}, store)
+	stats.Start(context.Background())
+
+	server := NewServer(stats)
+	go func() {
+		addr := os.Getenv("SERVER_ADDR")
+		if addr == "" {
+			addr = ":8080"
+		}
+		if err := server.ListenAndServe(addr); err != nil {
+			logrus.Errorf("Stats server stopped: %v", err)
+		}
+	}()
 
 	// Run for 1 minute then stop
 	logrus.Infof("Tracking stats for 1 minute...")
 	time.Sleep(1 * time.Minute)
 	stats.Stop()
+	stats.Wait()
 }
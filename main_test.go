@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -13,11 +14,15 @@ type MockRedditClient struct {
 	mock.Mock
 }
 
-func (m *MockRedditClient) FetchPosts(subreddit string) ([]*reddit.Post, error) {
+func (m *MockRedditClient) FetchPosts(ctx context.Context, subreddit string) ([]*reddit.Post, error) {
 	args := m.Called(subreddit)
 	return args.Get(0).([]*reddit.Post), args.Error(1)
 }
 
+func (m *MockRedditClient) OnRequestCompleted(hook RequestCompletionHook) {}
+
+func (m *MockRedditClient) RateLimiter() RateLimiter { return nil }
+
 func TestFetchPosts(t *testing.T) {
 	mockClient := new(MockRedditClient)
 	mockClient.On("FetchPosts", "golang").Return([]*reddit.Post{
@@ -25,7 +30,7 @@ func TestFetchPosts(t *testing.T) {
 		{Title: "Post 2", Score: 50},
 	}, nil)
 
-	posts, err := mockClient.FetchPosts("golang")
+	posts, err := mockClient.FetchPosts(context.Background(), "golang")
 	assert.NoError(t, err)
 	assert.Equal(t, 2, len(posts))
 	assert.Equal(t, "Post 1", posts[0].Title)
@@ -39,22 +44,66 @@ func TestUpdateStats(t *testing.T) {
 		{Title: "Post 2", Author: "user2", Score: 50},
 	}, nil)
 
-	stats := NewStats(mockClient, "golang")
+	stats := NewStats(mockClient, []string{"golang"}, nil)
+	stats.subCancels["golang"] = func() {}
 
-	// Start the stats processing in a goroutine
+	// Feed posts in, then cancel to signal updateStats to stop.
+	ctx, cancel := context.WithCancel(context.Background())
 	go func() {
-		stats.postsCh <- &reddit.Post{Title: "Post 1", Author: "user1", Score: 100}
-		stats.postsCh <- &reddit.Post{Title: "Post 2", Author: "user2", Score: 50}
-		close(stats.postsCh)
+		stats.postsCh <- trackedPost{post: &reddit.Post{ID: "p1", Title: "Post 1", Author: "user1", Score: 100}, subreddit: "golang"}
+		stats.postsCh <- trackedPost{post: &reddit.Post{ID: "p2", Title: "Post 2", Author: "user2", Score: 50}, subreddit: "golang"}
+		cancel()
 	}()
 
-	// Update stats and ensure processing stops when the channel is closed
-	stats.updateStats()
+	// Update stats and ensure processing stops once ctx is cancelled.
+	stats.updateStats(ctx)
 
 	// Assertions
-	assert.Equal(t, 2, len(stats.topPosts))
-	assert.Equal(t, "user1", stats.topPosts[0].Author)
-	assert.Equal(t, 100, stats.topPosts[0].Score)
+	top := stats.topPosts["golang"].TopN(defaultTopN)
+	assert.Equal(t, 2, len(top))
+	assert.Equal(t, "user1", top[0].Author)
+	assert.Equal(t, 100, top[0].Score)
 	assert.Equal(t, 1, stats.userPosts["user1"])
 	assert.Equal(t, 1, stats.userPosts["user2"])
+	assert.Equal(t, 2, len(stats.globalTop))
+}
+
+func TestSubscribeUnsubscribe(t *testing.T) {
+	mockClient := new(MockRedditClient)
+	stats := NewStats(mockClient, nil, nil)
+	stats.ctx, stats.cancel = context.WithCancel(context.Background())
+	defer stats.cancel()
+
+	assert.NoError(t, stats.Subscribe("golang"))
+	assert.Error(t, stats.Subscribe("golang"), "subscribing twice should fail")
+
+	assert.NoError(t, stats.Unsubscribe("golang"))
+	assert.Error(t, stats.Unsubscribe("golang"), "unsubscribing twice should fail")
+
+	_, stillTracked := stats.topPosts["golang"]
+	assert.False(t, stillTracked, "Unsubscribe should drop the subreddit's leaderboard")
+}
+
+func TestProcessPostDropsUnsubscribedSubreddit(t *testing.T) {
+	mockClient := new(MockRedditClient)
+	stats := NewStats(mockClient, nil, nil)
+
+	// Simulate a post that reached postsCh's select just as Unsubscribe won
+	// the race: the subreddit is no longer in subCancels, so processPost
+	// must not resurrect its leaderboard entry.
+	stats.processPost(trackedPost{
+		post:      &reddit.Post{ID: "p1", Title: "Post 1", Author: "user1", Score: 100},
+		subreddit: "golang",
+	})
+
+	_, tracked := stats.topPosts["golang"]
+	assert.False(t, tracked, "processPost should drop posts for a subreddit no longer in subCancels")
+	assert.Equal(t, 0, stats.userPosts["user1"])
+}
+
+func TestSubscribeBeforeStart(t *testing.T) {
+	mockClient := new(MockRedditClient)
+	stats := NewStats(mockClient, nil, nil)
+
+	assert.Equal(t, errNotStarted, stats.Subscribe("golang"))
 }
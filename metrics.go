@@ -0,0 +1,34 @@
+package main
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var (
+	requestsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "reddit_stats_tracker_requests_total",
+		Help: "Total number of requests made to the Reddit API, by subreddit.",
+	}, []string{"subreddit"})
+
+	requestsRateLimited = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "reddit_stats_tracker_requests_rate_limited_total",
+		Help: "Total number of requests that received a 429 response, by subreddit.",
+	}, []string{"subreddit"})
+
+	requestDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name: "reddit_stats_tracker_request_duration_seconds",
+		Help: "Latency of requests made to the Reddit API, by subreddit.",
+	}, []string{"subreddit"})
+)
+
+// observeRequest records Prometheus metrics for a single Reddit API call.
+func observeRequest(subreddit string, statusCode int, duration time.Duration) {
+	requestsTotal.WithLabelValues(subreddit).Inc()
+	requestDuration.WithLabelValues(subreddit).Observe(duration.Seconds())
+	if statusCode == 429 {
+		requestsRateLimited.WithLabelValues(subreddit).Inc()
+	}
+}
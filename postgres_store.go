@@ -0,0 +1,114 @@
+package main
+
+import (
+	"database/sql"
+	"time"
+
+	_ "github.com/lib/pq"
+)
+
+// postgresSchema creates the post_snapshots table if it doesn't already exist.
+const postgresSchema = `
+CREATE TABLE IF NOT EXISTS post_snapshots (
+	id         SERIAL PRIMARY KEY,
+	post_id    TEXT NOT NULL,
+	subreddit  TEXT NOT NULL,
+	title      TEXT NOT NULL,
+	author     TEXT NOT NULL,
+	score      INTEGER NOT NULL,
+	comments   INTEGER NOT NULL,
+	created_at TIMESTAMPTZ NOT NULL
+);
+CREATE INDEX IF NOT EXISTS idx_post_snapshots_post_id ON post_snapshots (post_id);
+CREATE INDEX IF NOT EXISTS idx_post_snapshots_author ON post_snapshots (author);
+CREATE INDEX IF NOT EXISTS idx_post_snapshots_created_at ON post_snapshots (created_at);
+`
+
+// PostgresStore is a StatsStore backed by a Postgres database.
+type PostgresStore struct {
+	db *sql.DB
+}
+
+// NewPostgresStore connects to Postgres using connStr and ensures the
+// schema is up to date.
+func NewPostgresStore(connStr string) (*PostgresStore, error) {
+	db, err := sql.Open("postgres", connStr)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := db.Exec(postgresSchema); err != nil {
+		db.Close()
+		return nil, err
+	}
+	return &PostgresStore{db: db}, nil
+}
+
+// SavePost implements StatsStore.
+func (s *PostgresStore) SavePost(snapshot PostSnapshot) error {
+	_, err := s.db.Exec(
+		`INSERT INTO post_snapshots (post_id, subreddit, title, author, score, comments, created_at)
+		 VALUES ($1, $2, $3, $4, $5, $6, $7)`,
+		snapshot.ID, snapshot.Subreddit, snapshot.Title, snapshot.Author,
+		snapshot.Score, snapshot.Comments, snapshot.Timestamp,
+	)
+	return err
+}
+
+// TopPostsSince implements StatsStore.
+func (s *PostgresStore) TopPostsSince(t time.Time, limit int) ([]PostSnapshot, error) {
+	rows, err := s.db.Query(
+		`WITH latest AS (
+			SELECT *, ROW_NUMBER() OVER (PARTITION BY post_id ORDER BY created_at DESC) AS rn
+			FROM post_snapshots
+			WHERE created_at >= $1
+		 )
+		 SELECT post_id, subreddit, title, author, score, comments, created_at
+		 FROM latest
+		 WHERE rn = 1
+		 ORDER BY score DESC
+		 LIMIT $2`,
+		t, limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	return scanSnapshots(rows)
+}
+
+// UserActivity implements StatsStore.
+func (s *PostgresStore) UserActivity(user string, window time.Duration) ([]PostSnapshot, error) {
+	rows, err := s.db.Query(
+		`SELECT post_id, subreddit, title, author, score, comments, created_at
+		 FROM post_snapshots
+		 WHERE author = $1 AND created_at >= $2
+		 ORDER BY created_at ASC`,
+		user, time.Now().Add(-window),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	return scanSnapshots(rows)
+}
+
+// ScoreSeries implements StatsStore.
+func (s *PostgresStore) ScoreSeries(postID string) ([]PostSnapshot, error) {
+	rows, err := s.db.Query(
+		`SELECT post_id, subreddit, title, author, score, comments, created_at
+		 FROM post_snapshots
+		 WHERE post_id = $1
+		 ORDER BY created_at ASC`,
+		postID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	return scanSnapshots(rows)
+}
+
+// Close implements StatsStore.
+func (s *PostgresStore) Close() error {
+	return s.db.Close()
+}
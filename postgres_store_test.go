@@ -0,0 +1,138 @@
+package main
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// newTestPostgresStore connects to the Postgres instance named by
+// TEST_POSTGRES_URL and truncates post_snapshots so each test starts from a
+// clean table. It skips the test when the env var isn't set (no local
+// Postgres to test against) or when running with -short.
+func newTestPostgresStore(t *testing.T) *PostgresStore {
+	t.Helper()
+	if testing.Short() {
+		t.Skip("skipping Postgres-backed test in short mode")
+	}
+	connStr := os.Getenv("TEST_POSTGRES_URL")
+	if connStr == "" {
+		t.Skip("TEST_POSTGRES_URL not set, skipping Postgres-backed test")
+	}
+
+	store, err := NewPostgresStore(connStr)
+	if err != nil {
+		t.Fatalf("NewPostgresStore: %v", err)
+	}
+	if _, err := store.db.Exec("TRUNCATE TABLE post_snapshots"); err != nil {
+		t.Fatalf("TRUNCATE post_snapshots: %v", err)
+	}
+	t.Cleanup(func() { store.Close() })
+	return store
+}
+
+func savePostgresPost(t *testing.T, store *PostgresStore, snapshot PostSnapshot) {
+	t.Helper()
+	if err := store.SavePost(snapshot); err != nil {
+		t.Fatalf("SavePost: %v", err)
+	}
+}
+
+func TestPostgresStoreTopPostsSinceDedupesByLatestSnapshot(t *testing.T) {
+	store := newTestPostgresStore(t)
+	base := time.Now().Add(-time.Hour)
+
+	savePostgresPost(t, store, PostSnapshot{
+		ID: "p1", Subreddit: "golang", Title: "first seen", Author: "alice",
+		Score: 5, Comments: 1, Timestamp: base,
+	})
+	savePostgresPost(t, store, PostSnapshot{
+		ID: "p1", Subreddit: "golang", Title: "first seen", Author: "alice",
+		Score: 50, Comments: 9, Timestamp: base.Add(time.Minute),
+	})
+	savePostgresPost(t, store, PostSnapshot{
+		ID: "p2", Subreddit: "golang", Title: "other post", Author: "bob",
+		Score: 20, Comments: 2, Timestamp: base.Add(2 * time.Minute),
+	})
+
+	posts, err := store.TopPostsSince(base.Add(-time.Minute), 10)
+	assert.NoError(t, err)
+	if assert.Equal(t, 2, len(posts), "should return one row per post_id, not one per snapshot") {
+		assert.Equal(t, "p1", posts[0].ID)
+		assert.Equal(t, 50, posts[0].Score, "should keep the latest snapshot's score, not the first")
+		assert.Equal(t, 9, posts[0].Comments)
+		assert.Equal(t, "p2", posts[1].ID)
+	}
+}
+
+func TestPostgresStoreTopPostsSinceRespectsWindowAndLimit(t *testing.T) {
+	store := newTestPostgresStore(t)
+	now := time.Now()
+
+	savePostgresPost(t, store, PostSnapshot{
+		ID: "old", Subreddit: "golang", Title: "too old", Author: "alice",
+		Score: 100, Comments: 1, Timestamp: now.Add(-2 * time.Hour),
+	})
+	savePostgresPost(t, store, PostSnapshot{
+		ID: "recent1", Subreddit: "golang", Title: "recent", Author: "bob",
+		Score: 10, Comments: 1, Timestamp: now,
+	})
+	savePostgresPost(t, store, PostSnapshot{
+		ID: "recent2", Subreddit: "golang", Title: "recent too", Author: "carol",
+		Score: 20, Comments: 1, Timestamp: now,
+	})
+
+	posts, err := store.TopPostsSince(now.Add(-time.Minute), 1)
+	assert.NoError(t, err)
+	if assert.Equal(t, 1, len(posts)) {
+		assert.Equal(t, "recent2", posts[0].ID, "should be ordered by score descending and exclude the out-of-window post")
+	}
+}
+
+func TestPostgresStoreUserActivity(t *testing.T) {
+	store := newTestPostgresStore(t)
+	now := time.Now()
+
+	savePostgresPost(t, store, PostSnapshot{
+		ID: "p1", Subreddit: "golang", Title: "a", Author: "alice",
+		Score: 1, Comments: 0, Timestamp: now.Add(-time.Hour),
+	})
+	savePostgresPost(t, store, PostSnapshot{
+		ID: "p1", Subreddit: "golang", Title: "a", Author: "alice",
+		Score: 2, Comments: 0, Timestamp: now,
+	})
+	savePostgresPost(t, store, PostSnapshot{
+		ID: "p2", Subreddit: "golang", Title: "b", Author: "bob",
+		Score: 1, Comments: 0, Timestamp: now,
+	})
+
+	activity, err := store.UserActivity("alice", 2*time.Hour)
+	assert.NoError(t, err)
+	if assert.Equal(t, 2, len(activity)) {
+		assert.Equal(t, 1, activity[0].Score, "should be ordered oldest to newest")
+		assert.Equal(t, 2, activity[1].Score)
+	}
+}
+
+func TestPostgresStoreScoreSeries(t *testing.T) {
+	store := newTestPostgresStore(t)
+	base := time.Now().Add(-time.Hour)
+
+	savePostgresPost(t, store, PostSnapshot{
+		ID: "p1", Subreddit: "golang", Title: "a", Author: "alice",
+		Score: 1, Comments: 0, Timestamp: base,
+	})
+	savePostgresPost(t, store, PostSnapshot{
+		ID: "p1", Subreddit: "golang", Title: "a", Author: "alice",
+		Score: 5, Comments: 2, Timestamp: base.Add(30 * time.Minute),
+	})
+
+	series, err := store.ScoreSeries("p1")
+	assert.NoError(t, err)
+	if assert.Equal(t, 2, len(series)) {
+		assert.Equal(t, 1, series[0].Score)
+		assert.Equal(t, 5, series[1].Score)
+	}
+}
@@ -0,0 +1,76 @@
+package main
+
+import (
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// RateLimiter tracks Reddit's rate limit headers and recommends how long
+// the caller should wait before issuing its next request.
+type RateLimiter interface {
+	// Update records the remaining-quota/reset-window observed on resp.
+	Update(resp *http.Response)
+
+	// NextInterval returns how long to wait before the next request,
+	// growing as quota runs low and shrinking back as it recovers.
+	NextInterval() time.Duration
+}
+
+// headerRateLimiter implements RateLimiter by reading Reddit's
+// X-Ratelimit-Remaining and X-Ratelimit-Reset headers.
+type headerRateLimiter struct {
+	mu        sync.Mutex
+	min       time.Duration
+	max       time.Duration
+	remaining float64
+	reset     time.Duration
+}
+
+// NewHeaderRateLimiter returns a RateLimiter that adapts between min and max
+// based on Reddit's rate limit response headers, starting at max until the
+// first response is observed.
+func NewHeaderRateLimiter(min, max time.Duration) RateLimiter {
+	return &headerRateLimiter{min: min, max: max, remaining: -1}
+}
+
+// Update implements RateLimiter.
+func (r *headerRateLimiter) Update(resp *http.Response) {
+	if resp == nil {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if v := resp.Header.Get("X-Ratelimit-Remaining"); v != "" {
+		if remaining, err := strconv.ParseFloat(v, 64); err == nil {
+			r.remaining = remaining
+		}
+	}
+	if v := resp.Header.Get("X-Ratelimit-Reset"); v != "" {
+		if seconds, err := strconv.Atoi(v); err == nil {
+			r.reset = time.Duration(seconds) * time.Second
+		}
+	}
+}
+
+// NextInterval implements RateLimiter.
+func (r *headerRateLimiter) NextInterval() time.Duration {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.remaining < 0 || r.reset <= 0 {
+		return r.max
+	}
+
+	// Spread the remaining requests evenly across the time left until reset.
+	interval := r.reset / time.Duration(r.remaining+1)
+	if interval < r.min {
+		return r.min
+	}
+	if interval > r.max {
+		return r.max
+	}
+	return interval
+}
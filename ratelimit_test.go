@@ -0,0 +1,26 @@
+package main
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHeaderRateLimiterDefaultsToMax(t *testing.T) {
+	limiter := NewHeaderRateLimiter(1*time.Second, 10*time.Second)
+	assert.Equal(t, 10*time.Second, limiter.NextInterval())
+}
+
+func TestHeaderRateLimiterAdaptsToHeaders(t *testing.T) {
+	limiter := NewHeaderRateLimiter(1*time.Second, 10*time.Second)
+
+	resp := &http.Response{Header: http.Header{}}
+	resp.Header.Set("X-Ratelimit-Remaining", "1")
+	resp.Header.Set("X-Ratelimit-Reset", "2")
+	limiter.Update(resp)
+
+	// 2 seconds left for 1 remaining request (+1) works out to exactly the min.
+	assert.Equal(t, 1*time.Second, limiter.NextInterval())
+}
@@ -0,0 +1,138 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	wsWriteWait  = 10 * time.Second
+	wsPongWait   = 60 * time.Second
+	wsPingPeriod = (wsPongWait * 9) / 10
+)
+
+// Server exposes a Stats tracker over HTTP/JSON and a WebSocket live feed,
+// turning the tracker into a service consumable by dashboards rather than a
+// log-only CLI.
+type Server struct {
+	stats    *Stats
+	mux      *http.ServeMux
+	upgrader websocket.Upgrader
+}
+
+// NewServer builds a Server that reads from stats.
+func NewServer(stats *Stats) *Server {
+	s := &Server{
+		stats: stats,
+		mux:   http.NewServeMux(),
+		upgrader: websocket.Upgrader{
+			ReadBufferSize:  1024,
+			WriteBufferSize: 1024,
+		},
+	}
+
+	s.mux.HandleFunc("/stats/top", s.handleTop)
+	s.mux.HandleFunc("/stats/users", s.handleUsers)
+	s.mux.HandleFunc("/stats/subreddit/", s.handleSubreddit)
+	s.mux.HandleFunc("/ws", s.handleWS)
+	s.mux.Handle("/metrics", promhttp.Handler())
+
+	return s
+}
+
+// ListenAndServe starts the HTTP server on addr. It blocks until the server
+// stops, mirroring the standard library's http.ListenAndServe.
+func (s *Server) ListenAndServe(addr string) error {
+	return http.ListenAndServe(addr, s.mux)
+}
+
+// handleTop serves the merged cross-subreddit leaderboard.
+func (s *Server) handleTop(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, s.stats.GlobalTop())
+}
+
+// handleUsers serves each user's post count.
+func (s *Server) handleUsers(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, s.stats.UserCounts())
+}
+
+// handleSubreddit serves the leaderboard for a single subreddit named in the
+// URL path, e.g. /stats/subreddit/golang.
+func (s *Server) handleSubreddit(w http.ResponseWriter, r *http.Request) {
+	name := strings.TrimPrefix(r.URL.Path, "/stats/subreddit/")
+	if name == "" {
+		http.Error(w, "missing subreddit name", http.StatusBadRequest)
+		return
+	}
+	writeJSON(w, s.stats.TopN(name, defaultTopN))
+}
+
+// handleWS upgrades the connection and streams every new post and
+// leaderboard update as they're published on the underlying broadcaster.
+func (s *Server) handleWS(w http.ResponseWriter, r *http.Request) {
+	conn, err := s.upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		logrus.Errorf("Error upgrading websocket connection: %v", err)
+		return
+	}
+	defer conn.Close()
+
+	events := s.stats.SubscribeEvents()
+	defer s.stats.UnsubscribeEvents(events)
+
+	// gorilla/websocket requires a concurrent reader to process control
+	// frames (pings/pongs/close) and to notice the client going away;
+	// closed signals the write loop below to stop once that happens.
+	closed := make(chan struct{})
+	conn.SetReadDeadline(time.Now().Add(wsPongWait))
+	conn.SetPongHandler(func(string) error {
+		conn.SetReadDeadline(time.Now().Add(wsPongWait))
+		return nil
+	})
+	go func() {
+		defer close(closed)
+		for {
+			if _, _, err := conn.ReadMessage(); err != nil {
+				return
+			}
+		}
+	}()
+
+	ticker := time.NewTicker(wsPingPeriod)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case event, ok := <-events:
+			if !ok {
+				return
+			}
+			conn.SetWriteDeadline(time.Now().Add(wsWriteWait))
+			if err := conn.WriteJSON(event); err != nil {
+				logrus.Errorf("Error writing to websocket: %v", err)
+				return
+			}
+		case <-ticker.C:
+			conn.SetWriteDeadline(time.Now().Add(wsWriteWait))
+			if err := conn.WriteMessage(websocket.PingMessage, nil); err != nil {
+				return
+			}
+		case <-closed:
+			return
+		}
+	}
+}
+
+// writeJSON writes v to w as an application/json response.
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		logrus.Errorf("Error encoding JSON response: %v", err)
+	}
+}
@@ -0,0 +1,128 @@
+package main
+
+import (
+	"database/sql"
+	"time"
+
+	_ "github.com/mattn/go-sqlite3"
+)
+
+// sqliteSchema creates the post_snapshots table if it doesn't already exist.
+const sqliteSchema = `
+CREATE TABLE IF NOT EXISTS post_snapshots (
+	id         INTEGER PRIMARY KEY AUTOINCREMENT,
+	post_id    TEXT NOT NULL,
+	subreddit  TEXT NOT NULL,
+	title      TEXT NOT NULL,
+	author     TEXT NOT NULL,
+	score      INTEGER NOT NULL,
+	comments   INTEGER NOT NULL,
+	created_at DATETIME NOT NULL
+);
+CREATE INDEX IF NOT EXISTS idx_post_snapshots_post_id ON post_snapshots (post_id);
+CREATE INDEX IF NOT EXISTS idx_post_snapshots_author ON post_snapshots (author);
+CREATE INDEX IF NOT EXISTS idx_post_snapshots_created_at ON post_snapshots (created_at);
+`
+
+// SQLiteStore is a StatsStore backed by a local SQLite database.
+type SQLiteStore struct {
+	db *sql.DB
+}
+
+// NewSQLiteStore opens (creating if necessary) a SQLite database at path
+// and ensures the schema is up to date.
+func NewSQLiteStore(path string) (*SQLiteStore, error) {
+	db, err := sql.Open("sqlite3", path)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := db.Exec(sqliteSchema); err != nil {
+		db.Close()
+		return nil, err
+	}
+	return &SQLiteStore{db: db}, nil
+}
+
+// SavePost implements StatsStore.
+func (s *SQLiteStore) SavePost(snapshot PostSnapshot) error {
+	_, err := s.db.Exec(
+		`INSERT INTO post_snapshots (post_id, subreddit, title, author, score, comments, created_at)
+		 VALUES (?, ?, ?, ?, ?, ?, ?)`,
+		snapshot.ID, snapshot.Subreddit, snapshot.Title, snapshot.Author,
+		snapshot.Score, snapshot.Comments, snapshot.Timestamp,
+	)
+	return err
+}
+
+// TopPostsSince implements StatsStore.
+func (s *SQLiteStore) TopPostsSince(t time.Time, limit int) ([]PostSnapshot, error) {
+	rows, err := s.db.Query(
+		`WITH latest AS (
+			SELECT *, ROW_NUMBER() OVER (PARTITION BY post_id ORDER BY created_at DESC) AS rn
+			FROM post_snapshots
+			WHERE created_at >= ?
+		 )
+		 SELECT post_id, subreddit, title, author, score, comments, created_at
+		 FROM latest
+		 WHERE rn = 1
+		 ORDER BY score DESC
+		 LIMIT ?`,
+		t, limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	return scanSnapshots(rows)
+}
+
+// UserActivity implements StatsStore.
+func (s *SQLiteStore) UserActivity(user string, window time.Duration) ([]PostSnapshot, error) {
+	rows, err := s.db.Query(
+		`SELECT post_id, subreddit, title, author, score, comments, created_at
+		 FROM post_snapshots
+		 WHERE author = ? AND created_at >= ?
+		 ORDER BY created_at ASC`,
+		user, time.Now().Add(-window),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	return scanSnapshots(rows)
+}
+
+// ScoreSeries implements StatsStore.
+func (s *SQLiteStore) ScoreSeries(postID string) ([]PostSnapshot, error) {
+	rows, err := s.db.Query(
+		`SELECT post_id, subreddit, title, author, score, comments, created_at
+		 FROM post_snapshots
+		 WHERE post_id = ?
+		 ORDER BY created_at ASC`,
+		postID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	return scanSnapshots(rows)
+}
+
+// Close implements StatsStore.
+func (s *SQLiteStore) Close() error {
+	return s.db.Close()
+}
+
+// scanSnapshots reads all rows of a post_snapshots query into a slice.
+func scanSnapshots(rows *sql.Rows) ([]PostSnapshot, error) {
+	var snapshots []PostSnapshot
+	for rows.Next() {
+		var snap PostSnapshot
+		if err := rows.Scan(&snap.ID, &snap.Subreddit, &snap.Title, &snap.Author,
+			&snap.Score, &snap.Comments, &snap.Timestamp); err != nil {
+			return nil, err
+		}
+		snapshots = append(snapshots, snap)
+	}
+	return snapshots, rows.Err()
+}
@@ -0,0 +1,124 @@
+package main
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestSQLiteStore(t *testing.T) *SQLiteStore {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "stats.db")
+	store, err := NewSQLiteStore(path)
+	if err != nil {
+		t.Fatalf("NewSQLiteStore: %v", err)
+	}
+	t.Cleanup(func() { store.Close() })
+	return store
+}
+
+func savePost(t *testing.T, store *SQLiteStore, snapshot PostSnapshot) {
+	t.Helper()
+	if err := store.SavePost(snapshot); err != nil {
+		t.Fatalf("SavePost: %v", err)
+	}
+}
+
+func TestSQLiteStoreTopPostsSinceDedupesByLatestSnapshot(t *testing.T) {
+	store := newTestSQLiteStore(t)
+	base := time.Now().Add(-time.Hour)
+
+	savePost(t, store, PostSnapshot{
+		ID: "p1", Subreddit: "golang", Title: "first seen", Author: "alice",
+		Score: 5, Comments: 1, Timestamp: base,
+	})
+	savePost(t, store, PostSnapshot{
+		ID: "p1", Subreddit: "golang", Title: "first seen", Author: "alice",
+		Score: 50, Comments: 9, Timestamp: base.Add(time.Minute),
+	})
+	savePost(t, store, PostSnapshot{
+		ID: "p2", Subreddit: "golang", Title: "other post", Author: "bob",
+		Score: 20, Comments: 2, Timestamp: base.Add(2 * time.Minute),
+	})
+
+	posts, err := store.TopPostsSince(base.Add(-time.Minute), 10)
+	assert.NoError(t, err)
+	if assert.Equal(t, 2, len(posts), "should return one row per post_id, not one per snapshot") {
+		assert.Equal(t, "p1", posts[0].ID)
+		assert.Equal(t, 50, posts[0].Score, "should keep the latest snapshot's score, not the first")
+		assert.Equal(t, 9, posts[0].Comments)
+		assert.Equal(t, "p2", posts[1].ID)
+	}
+}
+
+func TestSQLiteStoreTopPostsSinceRespectsWindowAndLimit(t *testing.T) {
+	store := newTestSQLiteStore(t)
+	now := time.Now()
+
+	savePost(t, store, PostSnapshot{
+		ID: "old", Subreddit: "golang", Title: "too old", Author: "alice",
+		Score: 100, Comments: 1, Timestamp: now.Add(-2 * time.Hour),
+	})
+	savePost(t, store, PostSnapshot{
+		ID: "recent1", Subreddit: "golang", Title: "recent", Author: "bob",
+		Score: 10, Comments: 1, Timestamp: now,
+	})
+	savePost(t, store, PostSnapshot{
+		ID: "recent2", Subreddit: "golang", Title: "recent too", Author: "carol",
+		Score: 20, Comments: 1, Timestamp: now,
+	})
+
+	posts, err := store.TopPostsSince(now.Add(-time.Minute), 1)
+	assert.NoError(t, err)
+	if assert.Equal(t, 1, len(posts)) {
+		assert.Equal(t, "recent2", posts[0].ID, "should be ordered by score descending and exclude the out-of-window post")
+	}
+}
+
+func TestSQLiteStoreUserActivity(t *testing.T) {
+	store := newTestSQLiteStore(t)
+	now := time.Now()
+
+	savePost(t, store, PostSnapshot{
+		ID: "p1", Subreddit: "golang", Title: "a", Author: "alice",
+		Score: 1, Comments: 0, Timestamp: now.Add(-time.Hour),
+	})
+	savePost(t, store, PostSnapshot{
+		ID: "p1", Subreddit: "golang", Title: "a", Author: "alice",
+		Score: 2, Comments: 0, Timestamp: now,
+	})
+	savePost(t, store, PostSnapshot{
+		ID: "p2", Subreddit: "golang", Title: "b", Author: "bob",
+		Score: 1, Comments: 0, Timestamp: now,
+	})
+
+	activity, err := store.UserActivity("alice", 2*time.Hour)
+	assert.NoError(t, err)
+	if assert.Equal(t, 2, len(activity)) {
+		assert.Equal(t, 1, activity[0].Score, "should be ordered oldest to newest")
+		assert.Equal(t, 2, activity[1].Score)
+	}
+}
+
+func TestSQLiteStoreScoreSeries(t *testing.T) {
+	store := newTestSQLiteStore(t)
+	base := time.Now().Add(-time.Hour)
+
+	savePost(t, store, PostSnapshot{
+		ID: "p1", Subreddit: "golang", Title: "a", Author: "alice",
+		Score: 1, Comments: 0, Timestamp: base,
+	})
+	savePost(t, store, PostSnapshot{
+		ID: "p1", Subreddit: "golang", Title: "a", Author: "alice",
+		Score: 5, Comments: 2, Timestamp: base.Add(30 * time.Minute),
+	})
+
+	series, err := store.ScoreSeries("p1")
+	assert.NoError(t, err)
+	if assert.Equal(t, 2, len(series)) {
+		assert.Equal(t, 1, series[0].Score)
+		assert.Equal(t, 5, series[1].Score)
+	}
+}
@@ -0,0 +1,36 @@
+package main
+
+import "time"
+
+// PostSnapshot captures a single post's metrics at a point in time.
+type PostSnapshot struct {
+	ID        string
+	Subreddit string
+	Title     string
+	Author    string
+	Score     int
+	Comments  int
+	Timestamp time.Time
+}
+
+// StatsStore persists post snapshots so that historical trends can be
+// queried across process restarts, not just held in memory.
+type StatsStore interface {
+	// SavePost records a snapshot of a post's current score/comment count.
+	SavePost(snapshot PostSnapshot) error
+
+	// TopPostsSince returns the highest scoring posts seen since t, most
+	// recent snapshot per post, ordered by score descending.
+	TopPostsSince(t time.Time, limit int) ([]PostSnapshot, error)
+
+	// UserActivity returns every snapshot authored by user within the
+	// trailing window, ordered oldest to newest.
+	UserActivity(user string, window time.Duration) ([]PostSnapshot, error)
+
+	// ScoreSeries returns the recorded score-over-time series for a single
+	// post, ordered oldest to newest.
+	ScoreSeries(postID string) ([]PostSnapshot, error)
+
+	// Close releases any underlying resources (DB handles, etc).
+	Close() error
+}
@@ -0,0 +1,108 @@
+package main
+
+import (
+	"container/heap"
+	"sort"
+
+	"github.com/vartanbeno/go-reddit/v2/reddit"
+)
+
+// defaultTopN is the number of posts kept per subreddit leaderboard.
+const defaultTopN = 10
+
+// heapEntry wraps a post with its current index in the underlying
+// container/heap slice, so Add can heap.Fix it in place.
+type heapEntry struct {
+	post  *reddit.Post
+	index int
+}
+
+// postHeap is a min-heap of heapEntry ordered by score, so the lowest
+// scoring post (the first one to evict) is always at the root.
+type postHeap []*heapEntry
+
+func (h postHeap) Len() int           { return len(h) }
+func (h postHeap) Less(i, j int) bool { return h[i].post.Score < h[j].post.Score }
+func (h postHeap) Swap(i, j int) {
+	h[i], h[j] = h[j], h[i]
+	h[i].index = i
+	h[j].index = j
+}
+
+func (h *postHeap) Push(x interface{}) {
+	entry := x.(*heapEntry)
+	entry.index = len(*h)
+	*h = append(*h, entry)
+}
+
+func (h *postHeap) Pop() interface{} {
+	old := *h
+	n := len(old)
+	entry := old[n-1]
+	old[n-1] = nil
+	*h = old[:n-1]
+	return entry
+}
+
+// topHeap keeps the top-scoring K posts seen for a subreddit, deduplicated
+// by post ID so that repeated sightings of the same post (Reddit returns the
+// same post across ticks with an updated score) update in place rather than
+// being counted twice.
+type topHeap struct {
+	capacity int
+	entries  postHeap
+	byID     map[string]*heapEntry
+}
+
+// newTopHeap creates a topHeap that retains at most capacity posts.
+func newTopHeap(capacity int) *topHeap {
+	return &topHeap{
+		capacity: capacity,
+		byID:     make(map[string]*heapEntry),
+	}
+}
+
+// Add records a sighting of post, inserting it if there's room or if it
+// outscores the current minimum, and updating its score in place if it was
+// already being tracked.
+func (h *topHeap) Add(post *reddit.Post) {
+	if entry, exists := h.byID[post.ID]; exists {
+		entry.post = post
+		heap.Fix(&h.entries, entry.index)
+		return
+	}
+
+	if len(h.entries) < h.capacity {
+		entry := &heapEntry{post: post}
+		heap.Push(&h.entries, entry)
+		h.byID[post.ID] = entry
+		return
+	}
+
+	if len(h.entries) > 0 && post.Score > h.entries[0].post.Score {
+		delete(h.byID, h.entries[0].post.ID)
+		entry := h.entries[0]
+		entry.post = post
+		h.byID[post.ID] = entry
+		heap.Fix(&h.entries, 0)
+	}
+}
+
+// TopN returns a sorted (highest score first) snapshot of up to k posts,
+// without mutating the heap.
+func (h *topHeap) TopN(k int) []*reddit.Post {
+	snapshot := make(postHeap, len(h.entries))
+	copy(snapshot, h.entries)
+	sort.Slice(snapshot, func(i, j int) bool {
+		return snapshot[i].post.Score > snapshot[j].post.Score
+	})
+
+	if k > len(snapshot) {
+		k = len(snapshot)
+	}
+	result := make([]*reddit.Post, k)
+	for i := 0; i < k; i++ {
+		result[i] = snapshot[i].post
+	}
+	return result
+}
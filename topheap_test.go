@@ -0,0 +1,29 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vartanbeno/go-reddit/v2/reddit"
+)
+
+func TestTopHeapEvictsLowestScore(t *testing.T) {
+	h := newTopHeap(2)
+	h.Add(&reddit.Post{ID: "a", Score: 10})
+	h.Add(&reddit.Post{ID: "b", Score: 20})
+	h.Add(&reddit.Post{ID: "c", Score: 5})  // below capacity's minimum, dropped
+	h.Add(&reddit.Post{ID: "d", Score: 30}) // evicts "a"
+
+	top := h.TopN(2)
+	assert.Equal(t, []string{"d", "b"}, []string{top[0].ID, top[1].ID})
+}
+
+func TestTopHeapDeduplicatesByID(t *testing.T) {
+	h := newTopHeap(2)
+	h.Add(&reddit.Post{ID: "a", Score: 10})
+	h.Add(&reddit.Post{ID: "a", Score: 99})
+
+	top := h.TopN(2)
+	assert.Equal(t, 1, len(top))
+	assert.Equal(t, 99, top[0].Score)
+}